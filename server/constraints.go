@@ -1,14 +1,23 @@
 package server
 
 import (
+	"container/list"
 	"errors"
+	"sync"
+
+	boostBls "github.com/flashbots/go-boost-utils/bls"
+	"github.com/chainbound/shardmap"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
 	gethCommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	lru "github.com/hashicorp/golang-lru/v2"
 )
 
+// defaultConstraintsCacheMaxSlots bounds how many distinct slots ConstraintsCache retains
+// before it starts evicting the oldest one, so a long-running observer watching "thousands of
+// constraints per slot across many upcoming slots" doesn't grow without bound.
+const defaultConstraintsCacheMaxSlots = 64
+
 type (
 	BatchedSignedConstraints = []*SignedConstraints
 	HashToTransactionDecoded = map[gethCommon.Hash]*types.Transaction
@@ -38,73 +47,217 @@ func (m *ConstraintsMessage) String() string {
 	return JSONStringify(m)
 }
 
+// VerifySignature verifies the BLS signature over Message against the constraints-API signing
+// domain, using the public key carried in Message.Pubkey. The signing root is the canonical
+// SSZ hash-tree-root of Message mixed with domain.
+func (s *SignedConstraints) VerifySignature(domain phase0.Domain) (bool, error) {
+	signingRoot, err := computeSigningRoot(&s.Message, domain)
+	if err != nil {
+		return false, err
+	}
+	return boostBls.VerifySignatureBytes(signingRoot[:], s.Signature[:], s.Message.Pubkey[:])
+}
+
 // TransactionHashMap is a map of transaction hashes to transactions that have
 // been marshalled without the blob sidecar.
 type TransactionHashMap = map[gethCommon.Hash]*HexTransaction
 
-// ConstraintsCache is a cache for constraints.
+// constraintEntry is the value stored in ConstraintsCache's shard map, pairing a constrained
+// transaction with the slot it was constrained for.
+type constraintEntry struct {
+	slot uint64
+	tx   *HexTransaction
+}
+
+// ConstraintsCache is a cache for constraints, keyed by (slot, transaction hash). Transactions
+// are sharded by hash in a concurrent shardmap.Map so FindTransactionByHash is O(1); a secondary
+// index by slot supports Delete and GetAll without scanning every entry in the shard map.
+//
+// bySlot and the shard map are both mutated under mu, even though shardmap.Map is itself safe
+// for concurrent use: Add and Delete must not interleave for the same slot, or a constraint
+// added concurrently with that slot's eviction could be written to the shard map after Delete
+// already cleared bySlot for it, leaking an entry that nothing will ever clean up again.
 type ConstraintsCache struct {
-	// map of slots to all constraints for that slot
-	constraints *lru.Cache[uint64, TransactionHashMap]
+	constraints *shardmap.Map[gethCommon.Hash, constraintEntry]
+
+	mu        sync.Mutex
+	bySlot    map[uint64]map[gethCommon.Hash]struct{}
+	slotOrder *list.List               // oldest-added slot at the front
+	slotElems map[uint64]*list.Element // slot -> its node in slotOrder
+	maxSlots  int
 }
 
-// NewConstraintsCache creates a new constraint cache.
-// cap is the maximum number of slots to store constraints for.
-func NewConstraintsCache(cap int) *ConstraintsCache {
-	constraints, _ := lru.New[uint64, TransactionHashMap](cap)
+// NewConstraintsCache creates a new constraint cache whose transaction-hash index is split
+// across shardCount shards, retaining constraints for at most maxSlots distinct slots. Once
+// that many slots are tracked, adding a constraint for a new slot evicts the oldest one.
+func NewConstraintsCache(shardCount int, maxSlots int) *ConstraintsCache {
+	if maxSlots <= 0 {
+		maxSlots = defaultConstraintsCacheMaxSlots
+	}
 	return &ConstraintsCache{
-		constraints: constraints,
+		constraints: shardmap.New[gethCommon.Hash, constraintEntry](shardCount),
+		bySlot:      make(map[uint64]map[gethCommon.Hash]struct{}),
+		slotOrder:   list.New(),
+		slotElems:   make(map[uint64]*list.Element),
+		maxSlots:    maxSlots,
 	}
 }
 
-// AddInclusionConstraints adds multiple inclusion constraints to the cache at the given slot
+// canonicalizeConstraintTransaction decodes txRaw and strips its blob sidecar, returning the
+// canonical hash constraints are keyed by together with the sidecar-free encoding. Any caller
+// that needs to derive the same identity AddInclusionConstraints uses for a transaction (e.g.
+// the SSE stream client deduplicating across relays) must go through this helper rather than
+// hashing the raw wire bytes, which differ for blob-carrying transactions.
+func canonicalizeConstraintTransaction(txRaw *HexTransaction) (gethCommon.Hash, *HexTransaction, error) {
+	if txRaw == nil {
+		return gethCommon.Hash{}, nil, errors.New("cannot add nil transaction")
+	}
+
+	txDecoded := new(types.Transaction)
+	if err := txDecoded.UnmarshalBinary(*txRaw); err != nil {
+		return gethCommon.Hash{}, nil, err
+	}
+
+	txDecoded = txDecoded.WithoutBlobTxSidecar()
+	txWithoutBlobSidecarRaw, err := txDecoded.MarshalBinary()
+	if err != nil {
+		return gethCommon.Hash{}, nil, err
+	}
+	hex := HexTransaction(txWithoutBlobSidecarRaw)
+
+	return txDecoded.Hash(), &hex, nil
+}
+
+// AddInclusionConstraints adds multiple inclusion constraints to the cache at the given slot.
 func (c *ConstraintsCache) AddInclusionConstraints(slot uint64, transactions []*HexTransaction) error {
 	if len(transactions) == 0 {
 		return nil
 	}
 
-	m, exists := c.constraints.Get(slot)
-	if !exists {
-		c.constraints.Add(slot, make(TransactionHashMap))
-	}
-
+	canonical := make([]struct {
+		hash gethCommon.Hash
+		tx   *HexTransaction
+	}, 0, len(transactions))
 	for _, txRaw := range transactions {
-		if txRaw == nil {
-			return errors.New("cannot add nil transaction")
-		}
-
-		txDecoded := new(types.Transaction)
-		err := txDecoded.UnmarshalBinary(*txRaw)
+		hash, tx, err := canonicalizeConstraintTransaction(txRaw)
 		if err != nil {
 			return err
 		}
+		canonical = append(canonical, struct {
+			hash gethCommon.Hash
+			tx   *HexTransaction
+		}{hash, tx})
+	}
 
-		txDecoded = txDecoded.WithoutBlobTxSidecar()
-		txWithoutblobSidecarRaw, err := txDecoded.MarshalBinary()
-		if err != nil {
-			return err
-		}
-		hex := HexTransaction(txWithoutblobSidecarRaw)
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
-		m[txDecoded.Hash()] = &hex
+	slotHashes, exists := c.bySlot[slot]
+	if !exists {
+		slotHashes = make(map[gethCommon.Hash]struct{}, len(canonical))
+		c.bySlot[slot] = slotHashes
+		c.slotElems[slot] = c.slotOrder.PushBack(slot)
+	}
+	for _, entry := range canonical {
+		c.constraints.Set(entry.hash, constraintEntry{slot: slot, tx: entry.tx})
+		slotHashes[entry.hash] = struct{}{}
 	}
 
+	c.evictOldestLocked()
+
 	return nil
 }
 
-// Get gets the constraints at the given slot.
+// evictOldestLocked drops the oldest tracked slots until at most c.maxSlots remain. Must be
+// called with c.mu held.
+func (c *ConstraintsCache) evictOldestLocked() {
+	for len(c.bySlot) > c.maxSlots {
+		oldest := c.slotOrder.Front()
+		if oldest == nil {
+			return
+		}
+		c.deleteLocked(oldest.Value.(uint64))
+	}
+}
+
+// Delete evicts every constraint cached for slot.
+func (c *ConstraintsCache) Delete(slot uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deleteLocked(slot)
+}
+
+// deleteLocked removes slot from bySlot, slotOrder and the shard map. Must be called with c.mu
+// held, so it can never interleave with an AddInclusionConstraints for the same slot.
+func (c *ConstraintsCache) deleteLocked(slot uint64) {
+	slotHashes, exists := c.bySlot[slot]
+	if !exists {
+		return
+	}
+	delete(c.bySlot, slot)
+
+	if elem, ok := c.slotElems[slot]; ok {
+		c.slotOrder.Remove(elem)
+		delete(c.slotElems, slot)
+	}
+
+	for hash := range slotHashes {
+		c.constraints.Delete(hash)
+	}
+}
+
+// GetAll atomically returns every transaction constrained for slot.
+//
+// Unlike before the Add/Delete race fix, this holds the same single mu that
+// AddInclusionConstraints and Delete use for every slot, not just slot: a long-running observer
+// with thousands of constraints cached across many upcoming slots will have GetAll(slotA)
+// serialize against writers for slotB. Reintroducing the old per-slot isolation (e.g. striped or
+// per-slot locks) is a follow-up, not something this change attempts.
+func (c *ConstraintsCache) GetAll(slot uint64) []*HexTransaction {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	slotHashes := c.bySlot[slot]
+	txs := make([]*HexTransaction, 0, len(slotHashes))
+	for hash := range slotHashes {
+		if entry, ok := c.constraints.Get(hash); ok {
+			txs = append(txs, entry.tx)
+		}
+	}
+	return txs
+}
+
+// Get returns the constraints cached for slot as a TransactionHashMap, for callers that need
+// hash-keyed lookups scoped to a single slot (e.g. inclusion proof verification).
 func (c *ConstraintsCache) Get(slot uint64) (TransactionHashMap, bool) {
-	return c.constraints.Get(slot)
+	c.mu.Lock()
+	slotHashes, exists := c.bySlot[slot]
+	hashes := make([]gethCommon.Hash, 0, len(slotHashes))
+	for hash := range slotHashes {
+		hashes = append(hashes, hash)
+	}
+	c.mu.Unlock()
+	if !exists {
+		return nil, false
+	}
+
+	m := make(TransactionHashMap, len(hashes))
+	for _, hash := range hashes {
+		if entry, ok := c.constraints.Get(hash); ok {
+			m[hash] = entry.tx
+		}
+	}
+	return m, true
 }
 
-// FindTransactionByHash finds the constraint for the given transaction hash and returns it.
+// FindTransactionByHash finds the constraint for the given transaction hash and returns it in
+// O(1), independent of how many slots are currently cached.
 func (c *ConstraintsCache) FindTransactionByHash(txHash gethCommon.Hash) (*HexTransaction, bool) {
-	for _, hashToTx := range c.constraints.Values() {
-		if tx, exists := hashToTx[txHash]; exists {
-			return tx, true
-		}
+	entry, ok := c.constraints.Get(txHash)
+	if !ok {
+		return nil, false
 	}
-	return nil, false
+	return entry.tx, true
 }
 
 // SignedDelegation represents the delegation signed by the proposer pubkey to
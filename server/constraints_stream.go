@@ -0,0 +1,253 @@
+package server
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	gethCommon "github.com/ethereum/go-ethereum/common"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+const constraintsStreamPath = "/relay/v1/builder/constraints_stream"
+
+// streamSeenRetentionSlots bounds how many past slots ConstraintsStreamClient.seen keeps
+// dedup entries for. Slots older than the highest slot seen so far by more than this are
+// pruned, so a long-running client doesn't grow seen without bound.
+const streamSeenRetentionSlots = 32
+
+// ProposerForSlotFunc resolves the proposer pubkey for a given slot, so
+// ConstraintsStreamClient can check that a constraint's signer is delegated by that slot's
+// actual proposer rather than by some validator.
+type ProposerForSlotFunc func(slot uint64) (phase0.BLSPubKey, bool)
+
+// ConstraintsStreamClient subscribes to a relay's SSE constraints_stream endpoint and feeds
+// decoded BatchedSignedConstraints into a ConstraintsCache, replacing the previous
+// submit-constraint push model with a pull model suitable for mev-boost acting as an observer.
+type ConstraintsStreamClient struct {
+	relayURL        string
+	domain          phase0.Domain
+	httpClient      *http.Client
+	cache           *ConstraintsCache
+	delegation      *DelegationRegistry
+	proposerForSlot ProposerForSlotFunc
+
+	seenMu      sync.Mutex
+	seen        map[uint64]map[gethCommon.Hash]struct{}
+	seenMaxSlot uint64
+
+	closeOnce sync.Once
+	closeCh   chan struct{}
+}
+
+// NewConstraintsStreamClient creates a client that streams constraints from relayURL into
+// cache, dropping any constraint whose signature does not verify against domain. When delegation
+// is non-nil, it also drops any constraint whose signer is not delegated by that slot's proposer
+// (resolved via proposerForSlot); when delegation is nil, no delegation check is made.
+func NewConstraintsStreamClient(relayURL string, domain phase0.Domain, cache *ConstraintsCache, delegation *DelegationRegistry, proposerForSlot ProposerForSlotFunc) *ConstraintsStreamClient {
+	return &ConstraintsStreamClient{
+		relayURL:        relayURL,
+		domain:          domain,
+		httpClient:      &http.Client{},
+		cache:           cache,
+		delegation:      delegation,
+		proposerForSlot: proposerForSlot,
+		seen:            make(map[uint64]map[gethCommon.Hash]struct{}),
+		closeCh:         make(chan struct{}),
+	}
+}
+
+// Start connects to the relay's constraints_stream endpoint and blocks, feeding decoded
+// constraints into the cache until ctx is cancelled or Close is called. Any stream error
+// triggers a reconnect with exponential backoff.
+func (c *ConstraintsStreamClient) Start(ctx context.Context) {
+	backoff := time.Second
+	const maxBackoff = 30 * time.Second
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.closeCh:
+			return
+		default:
+		}
+
+		if err := c.subscribeOnce(ctx); err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-c.closeCh:
+				return
+			case <-time.After(backoff):
+			}
+
+			backoff *= 2
+			if backoff > maxBackoff {
+				backoff = maxBackoff
+			}
+			continue
+		}
+
+		backoff = time.Second
+	}
+}
+
+// Close shuts down the stream client, causing Start to return.
+func (c *ConstraintsStreamClient) Close() {
+	c.closeOnce.Do(func() { close(c.closeCh) })
+}
+
+func (c *ConstraintsStreamClient) subscribeOnce(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-c.closeCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.relayURL+constraintsStreamPath, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("constraints stream returned status %d", resp.StatusCode)
+	}
+
+	body := resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data:") {
+			continue
+		}
+
+		data := strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		if data == "" {
+			continue
+		}
+
+		var batch BatchedSignedConstraints
+		if err := json.Unmarshal([]byte(data), &batch); err != nil {
+			continue
+		}
+		c.handleBatch(batch)
+	}
+
+	return scanner.Err()
+}
+
+// handleBatch verifies and deduplicates every constraint in batch before adding it to the cache.
+func (c *ConstraintsStreamClient) handleBatch(batch BatchedSignedConstraints) {
+	for _, signed := range batch {
+		if signed == nil {
+			continue
+		}
+
+		if c.delegation != nil {
+			if c.proposerForSlot == nil {
+				continue
+			}
+			proposer, ok := c.proposerForSlot(signed.Message.Slot)
+			if !ok || !c.delegation.IsAuthorized(proposer, signed.Message.Pubkey) {
+				continue
+			}
+		}
+
+		if ok, err := signed.VerifySignature(c.domain); err != nil || !ok {
+			continue
+		}
+
+		fresh := c.dedup(signed.Message.Slot, signed.Message.Transactions)
+		if len(fresh) == 0 {
+			continue
+		}
+
+		_ = c.cache.AddInclusionConstraints(signed.Message.Slot, fresh)
+	}
+}
+
+// dedup returns the subset of transactions that have not already been observed for slot by this
+// client, and marks them as seen. A ConstraintsStreamClient only ever streams from the single
+// relayURL it was constructed with, so seen gives no cross-relay coordination by itself — a
+// caller running one client per relay gets that only incidentally, one layer down, from
+// ConstraintsCache keying constraints by the same canonical transaction hash dedup uses here.
+// Transactions are keyed the same way ConstraintsCache.AddInclusionConstraints keys them
+// (decoded, with the blob sidecar stripped), so the same transaction streamed with and without
+// its sidecar is still recognized as a duplicate once it reaches the cache.
+func (c *ConstraintsStreamClient) dedup(slot uint64, transactions []*HexTransaction) []*HexTransaction {
+	c.seenMu.Lock()
+	defer c.seenMu.Unlock()
+
+	slotSeen, exists := c.seen[slot]
+	if !exists {
+		slotSeen = make(map[gethCommon.Hash]struct{})
+		c.seen[slot] = slotSeen
+	}
+	if slot > c.seenMaxSlot {
+		c.seenMaxSlot = slot
+		c.pruneSeenLocked()
+	}
+
+	fresh := make([]*HexTransaction, 0, len(transactions))
+	for _, tx := range transactions {
+		if tx == nil {
+			continue
+		}
+		hash, _, err := canonicalizeConstraintTransaction(tx)
+		if err != nil {
+			continue
+		}
+		if _, seen := slotSeen[hash]; seen {
+			continue
+		}
+		slotSeen[hash] = struct{}{}
+		fresh = append(fresh, tx)
+	}
+	return fresh
+}
+
+// pruneSeenLocked drops dedup entries for slots older than c.seenMaxSlot by more than
+// streamSeenRetentionSlots. Must be called with c.seenMu held.
+func (c *ConstraintsStreamClient) pruneSeenLocked() {
+	if c.seenMaxSlot <= streamSeenRetentionSlots {
+		return
+	}
+	cutoff := c.seenMaxSlot - streamSeenRetentionSlots
+
+	for slot := range c.seen {
+		if slot < cutoff {
+			delete(c.seen, slot)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+
+	boostBls "github.com/flashbots/go-boost-utils/bls"
+	fastSsz "github.com/ferranbt/fastssz"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// Action codes carried by Delegation/Revocation messages.
+const (
+	delegationActionDelegate uint8 = 0
+	delegationActionRevoke   uint8 = 1
+)
+
+// DelegationRegistry tracks, per validator, the set of delegatee pubkeys currently authorized
+// to submit constraints on that validator's behalf, and persists that state to disk so it
+// survives restarts.
+type DelegationRegistry struct {
+	mu         sync.RWMutex
+	delegatees map[phase0.BLSPubKey]map[phase0.BLSPubKey]struct{}
+
+	statePath string
+}
+
+// delegationRegistryState is the JSON-serializable form of a DelegationRegistry, used for
+// persistence.
+type delegationRegistryState struct {
+	// Delegatees maps a validator pubkey (hex) to its currently authorized delegatee pubkeys (hex).
+	Delegatees map[string][]string `json:"delegatees"`
+}
+
+// NewDelegationRegistry creates a DelegationRegistry backed by statePath, loading any
+// previously persisted delegations from it. statePath is created on the first call to
+// Delegate or Revoke if it does not already exist.
+func NewDelegationRegistry(statePath string) (*DelegationRegistry, error) {
+	r := &DelegationRegistry{
+		delegatees: make(map[phase0.BLSPubKey]map[phase0.BLSPubKey]struct{}),
+		statePath:  statePath,
+	}
+
+	raw, err := os.ReadFile(statePath)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read delegation registry state: %w", err)
+	}
+
+	var state delegationRegistryState
+	if err := json.Unmarshal(raw, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode delegation registry state: %w", err)
+	}
+
+	for validatorHex, delegateeHexes := range state.Delegatees {
+		var validator phase0.BLSPubKey
+		if err := validator.UnmarshalText([]byte(validatorHex)); err != nil {
+			return nil, fmt.Errorf("failed to decode validator pubkey %q: %w", validatorHex, err)
+		}
+
+		delegatees := make(map[phase0.BLSPubKey]struct{}, len(delegateeHexes))
+		for _, delegateeHex := range delegateeHexes {
+			var delegatee phase0.BLSPubKey
+			if err := delegatee.UnmarshalText([]byte(delegateeHex)); err != nil {
+				return nil, fmt.Errorf("failed to decode delegatee pubkey %q: %w", delegateeHex, err)
+			}
+			delegatees[delegatee] = struct{}{}
+		}
+		r.delegatees[validator] = delegatees
+	}
+
+	return r, nil
+}
+
+// IsAuthorized reports whether delegatee is currently authorized to submit constraints on
+// behalf of validator.
+func (r *DelegationRegistry) IsAuthorized(validator, delegatee phase0.BLSPubKey) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	delegatees, exists := r.delegatees[validator]
+	if !exists {
+		return false
+	}
+	_, ok := delegatees[delegatee]
+	return ok
+}
+
+// IsActiveDelegatee reports whether pubkey is currently authorized to submit constraints on
+// behalf of at least one validator.
+func (r *DelegationRegistry) IsActiveDelegatee(pubkey phase0.BLSPubKey) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, delegatees := range r.delegatees {
+		if _, ok := delegatees[pubkey]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Delegate verifies signed against domain and, on success, authorizes
+// signed.Message.DelegateePubkey to submit constraints on behalf of
+// signed.Message.ValidatorPubkey.
+func (r *DelegationRegistry) Delegate(signed *SignedDelegation, domain phase0.Domain) error {
+	if signed.Message.Action != delegationActionDelegate {
+		return fmt.Errorf("unexpected action code %d for delegation", signed.Message.Action)
+	}
+
+	ok, err := verifyDelegationSignature(&signed.Message, signed.Signature, signed.Message.ValidatorPubkey, domain)
+	if err != nil {
+		return fmt.Errorf("failed to verify delegation signature: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("invalid delegation signature for validator %s", signed.Message.ValidatorPubkey.String())
+	}
+
+	r.mu.Lock()
+	delegatees, exists := r.delegatees[signed.Message.ValidatorPubkey]
+	if !exists {
+		delegatees = make(map[phase0.BLSPubKey]struct{})
+		r.delegatees[signed.Message.ValidatorPubkey] = delegatees
+	}
+	delegatees[signed.Message.DelegateePubkey] = struct{}{}
+	r.mu.Unlock()
+
+	return r.persist()
+}
+
+// Revoke verifies signed against domain and, on success, removes
+// signed.Message.DelegateePubkey's authorization to submit constraints on behalf of
+// signed.Message.ValidatorPubkey.
+func (r *DelegationRegistry) Revoke(signed *SignedRevocation, domain phase0.Domain) error {
+	if signed.Message.Action != delegationActionRevoke {
+		return fmt.Errorf("unexpected action code %d for revocation", signed.Message.Action)
+	}
+
+	ok, err := verifyDelegationSignature(&signed.Message, signed.Signature, signed.Message.ValidatorPubkey, domain)
+	if err != nil {
+		return fmt.Errorf("failed to verify revocation signature: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("invalid revocation signature for validator %s", signed.Message.ValidatorPubkey.String())
+	}
+
+	r.mu.Lock()
+	if delegatees, exists := r.delegatees[signed.Message.ValidatorPubkey]; exists {
+		delete(delegatees, signed.Message.DelegateePubkey)
+	}
+	r.mu.Unlock()
+
+	return r.persist()
+}
+
+// persist writes the current registry state to r.statePath.
+func (r *DelegationRegistry) persist() error {
+	r.mu.RLock()
+	state := delegationRegistryState{Delegatees: make(map[string][]string, len(r.delegatees))}
+	for validator, delegatees := range r.delegatees {
+		hexes := make([]string, 0, len(delegatees))
+		for delegatee := range delegatees {
+			hexes = append(hexes, delegatee.String())
+		}
+		state.Delegatees[validator.String()] = hexes
+	}
+	r.mu.RUnlock()
+
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode delegation registry state: %w", err)
+	}
+
+	if err := os.WriteFile(r.statePath, raw, 0o600); err != nil {
+		return fmt.Errorf("failed to write delegation registry state: %w", err)
+	}
+	return nil
+}
+
+// verifyDelegationSignature verifies signature over the SSZ hash-tree-root of msg mixed with
+// domain, using pubkey. Both Delegation and Revocation share this shape, so it is generic over
+// either via fastSsz.HashRoot.
+func verifyDelegationSignature(msg fastSsz.HashRoot, signature phase0.BLSSignature, pubkey phase0.BLSPubKey, domain phase0.Domain) (bool, error) {
+	signingRoot, err := computeSigningRoot(msg, domain)
+	if err != nil {
+		return false, err
+	}
+
+	return boostBls.VerifySignatureBytes(signingRoot[:], signature[:], pubkey[:])
+}
+
+// constraintsAuthorizationError is returned by CheckConstraintSubmissionAuthorized and is
+// intended to be serialized as the body of an HTTP 403 response.
+type constraintsAuthorizationError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *constraintsAuthorizationError) Error() string {
+	return e.Message
+}
+
+// CheckConstraintSubmissionAuthorized rejects signed unless its Message.Pubkey is an active
+// delegatee for the slot's proposer. The /constraints/v1/builder/constraints handler does not
+// call it, so that handler does not apply this check.
+func CheckConstraintSubmissionAuthorized(r *DelegationRegistry, proposer phase0.BLSPubKey, signed *SignedConstraints) error {
+	if !r.IsAuthorized(proposer, signed.Message.Pubkey) {
+		return &constraintsAuthorizationError{
+			Code:    403,
+			Message: fmt.Sprintf("pubkey %s is not an active delegatee for proposer %s", signed.Message.Pubkey.String(), proposer.String()),
+		}
+	}
+	return nil
+}
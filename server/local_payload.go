@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	consensusSpec "github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// LocalPayloadBuilder drives the engine-API forkchoiceUpdated/getPayload sequence against a
+// locally configured execution client, so a proposer can still produce a block when every
+// relay bid for a slot fails inclusion-proof verification.
+type LocalPayloadBuilder struct {
+	engine *rpc.Client
+}
+
+// NewLocalPayloadBuilder dials the engine-API endpoint at engineURL, as configured by the
+// caller, and returns a LocalPayloadBuilder backed by it.
+func NewLocalPayloadBuilder(ctx context.Context, engineURL string) (*LocalPayloadBuilder, error) {
+	client, err := rpc.DialContext(ctx, engineURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial local engine-API endpoint: %w", err)
+	}
+	return &LocalPayloadBuilder{engine: client}, nil
+}
+
+// BuildFallbackPayload triggers payload building on the local execution client for the given
+// forkchoice state and attributes, then retrieves and returns the payload built for version. It
+// is called once every bid returned by get_header_with_proofs has been rejected for the slot.
+func (b *LocalPayloadBuilder) BuildFallbackPayload(
+	ctx context.Context,
+	version consensusSpec.DataVersion,
+	forkchoiceState engine.ForkchoiceStateV1,
+	attrs *engine.PayloadAttributes,
+) (*engine.ExecutionPayloadEnvelope, error) {
+	var fcResp engine.ForkChoiceResponse
+	if err := b.engine.CallContext(ctx, &fcResp, "engine_forkchoiceUpdatedV3", forkchoiceState, attrs); err != nil {
+		return nil, fmt.Errorf("local forkchoiceUpdated failed: %w", err)
+	}
+	if fcResp.PayloadID == nil {
+		return nil, errors.New("local engine did not return a payload id")
+	}
+
+	getPayloadMethod, err := getPayloadMethodForVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	var envelope engine.ExecutionPayloadEnvelope
+	if err := b.engine.CallContext(ctx, &envelope, getPayloadMethod, *fcResp.PayloadID); err != nil {
+		return nil, fmt.Errorf("local getPayload failed: %w", err)
+	}
+
+	return &envelope, nil
+}
+
+// getPayloadMethodForVersion returns the engine_getPayload JSON-RPC method for version. Electra
+// introduced V4 to carry the additional execution requests field that V3 doesn't have, so the
+// method can't be hardcoded to V3 once Electra payloads are in play.
+func getPayloadMethodForVersion(version consensusSpec.DataVersion) (string, error) {
+	switch version {
+	case consensusSpec.DataVersionDeneb:
+		return "engine_getPayloadV3", nil
+	case consensusSpec.DataVersionElectra:
+		return "engine_getPayloadV4", nil
+	default:
+		return "", fmt.Errorf("unsupported data version %d for local payload fallback", version)
+	}
+}
+
+// Close shuts down the underlying engine-API connection.
+func (b *LocalPayloadBuilder) Close() {
+	b.engine.Close()
+}
+
+// SelectedPayload is returned by SelectVerifiedBid: either a relay bid whose inclusion proof
+// verified, or a locally built fallback payload when none did.
+type SelectedPayload struct {
+	Bid   *VersionedSignedBuilderBidWithProofs
+	Relay string
+
+	Fallback *engine.ExecutionPayloadEnvelope
+}
+
+// SelectVerifiedBid is the get_header_with_proofs auction step: it runs VerifyBidInclusionProof
+// against the relay bids for slot in map iteration order and returns the first one that passes,
+// without verifying the rest; which passing bid is returned is therefore not defined. If every
+// bid fails verification, it builds a payload locally via fallback instead of proposing an
+// unverifiable block.
+func SelectVerifiedBid(
+	ctx context.Context,
+	cache *ConstraintsCache,
+	slot uint64,
+	version consensusSpec.DataVersion,
+	txsRoot phase0.Root,
+	bids map[string]*VersionedSignedBuilderBidWithProofs,
+	fallback *LocalPayloadBuilder,
+	forkchoiceState engine.ForkchoiceStateV1,
+	attrs *engine.PayloadAttributes,
+) (*SelectedPayload, error) {
+	for relay, bid := range bids {
+		if err := VerifyBidInclusionProof(cache, slot, relay, txsRoot, bid); err != nil {
+			continue
+		}
+		return &SelectedPayload{Bid: bid, Relay: relay}, nil
+	}
+
+	if fallback == nil {
+		return nil, fmt.Errorf("no relay bid for slot %d passed inclusion proof verification and no local fallback is configured", slot)
+	}
+
+	envelope, err := fallback.BuildFallbackPayload(ctx, version, forkchoiceState, attrs)
+	if err != nil {
+		return nil, fmt.Errorf("local fallback payload build failed for slot %d: %w", slot, err)
+	}
+
+	return &SelectedPayload{Fallback: envelope}, nil
+}
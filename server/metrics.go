@@ -0,0 +1,17 @@
+package server
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// inclusionProofFailures counts builder bids dropped from the auction because their
+// constraint inclusion proof failed verification against the execution payload header.
+var inclusionProofFailures = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "bolt_inclusion_proof_failures_total",
+		Help: "Number of builder bids dropped because their inclusion proof failed verification",
+	},
+	[]string{"relay"},
+)
+
+func init() {
+	prometheus.MustRegister(inclusionProofFailures)
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+const octetStreamContentType = "application/octet-stream"
+
+// sszEncoder is implemented by every constraints-API type with a hand-written SSZ encoding,
+// so handlers can negotiate the response encoding generically.
+type sszEncoder interface {
+	MarshalSSZ() ([]byte, error)
+}
+
+// WriteNegotiatedResponse writes v as application/octet-stream when the request's Accept
+// header asks for it and v has an SSZ encoding, falling back to JSON (via jsonFallback)
+// otherwise.
+func WriteNegotiatedResponse(w http.ResponseWriter, r *http.Request, v any, jsonFallback func() ([]byte, error)) error {
+	if r.Header.Get("Accept") == octetStreamContentType {
+		if enc, ok := v.(sszEncoder); ok {
+			body, err := enc.MarshalSSZ()
+			if err != nil {
+				return err
+			}
+			w.Header().Set("Content-Type", octetStreamContentType)
+			_, err = w.Write(body)
+			return err
+		}
+	}
+
+	body, err := jsonFallback()
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(body)
+	return err
+}
+
+// DecodeNegotiatedConstraints decodes a SignedConstraints request body according to its
+// Content-Type, supporting both application/octet-stream (SSZ) and JSON.
+func DecodeNegotiatedConstraints(r *http.Request, body []byte) (*SignedConstraints, error) {
+	signed := new(SignedConstraints)
+
+	if r.Header.Get("Content-Type") == octetStreamContentType {
+		if err := signed.UnmarshalSSZ(body); err != nil {
+			return nil, err
+		}
+		return signed, nil
+	}
+
+	if err := json.Unmarshal(body, signed); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
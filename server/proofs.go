@@ -11,12 +11,18 @@ import (
 	fastSsz "github.com/ferranbt/fastssz"
 
 	"github.com/attestantio/go-builder-client/api/deneb"
+	"github.com/attestantio/go-builder-client/api/electra"
 	builderSpec "github.com/attestantio/go-builder-client/spec"
 	consensusSpec "github.com/attestantio/go-eth2-client/spec"
 
 	"github.com/attestantio/go-eth2-client/spec/phase0"
+	gethCommon "github.com/ethereum/go-ethereum/common"
 )
 
+// maxBytesPerTransaction is the consensus-specs `MAX_BYTES_PER_TRANSACTION` bound used to
+// hash-tree-root a single transaction as a `ByteList[MAX_BYTES_PER_TRANSACTION]`.
+const maxBytesPerTransaction = 1073741824
+
 // VersionSignedBuilderBidWithProofs is a wrapper struct over `builderSpec.VersionedSignedBuilderBid`
 // to include constraint inclusion proofs
 type VersionedSignedBuilderBidWithProofs struct {
@@ -38,6 +44,16 @@ func (v *VersionedSignedBuilderBidWithProofs) MarshalJSON() ([]byte, error) {
 			Signature: v.Deneb.Signature,
 			Proofs:    v.Proofs,
 		})
+	case consensusSpec.DataVersionElectra:
+		return json.Marshal(struct {
+			Message   *electra.BuilderBid `json:"message"`
+			Signature phase0.BLSSignature `json:"signature"`
+			Proofs    *InclusionProof     `json:"proofs"`
+		}{
+			Message:   v.Electra.Message,
+			Signature: v.Electra.Signature,
+			Proofs:    v.Proofs,
+		})
 	default:
 		return nil, fmt.Errorf("unknown or unsupported data version %d", v.Version)
 	}
@@ -82,6 +98,28 @@ func (v *VersionedSignedBuilderBidWithProofs) UnmarshalJSON(data []byte) error {
 
 		v.Proofs = dataBid.Data.Proofs
 
+		return nil
+	case consensusSpec.DataVersionElectra:
+		var dataBid struct {
+			Data struct {
+				Message   *electra.BuilderBid `json:"message"`
+				Signature phase0.BLSSignature `json:"signature"`
+				Proofs    *InclusionProof     `json:"proofs"`
+			} `json:"data"`
+		}
+
+		err = json.Unmarshal(data, &dataBid)
+		if err != nil {
+			return err
+		}
+
+		v.VersionedSignedBuilderBid = &builderSpec.VersionedSignedBuilderBid{
+			Version: partialBid.Version,
+			Electra: &electra.SignedBuilderBid{Message: dataBid.Data.Message, Signature: dataBid.Data.Signature},
+		}
+
+		v.Proofs = dataBid.Data.Proofs
+
 		return nil
 	default:
 		return fmt.Errorf(
@@ -129,9 +167,19 @@ func (h *HexBytes) UnmarshalJSON(input []byte) error {
 	}
 
 	var data string
-	json.Unmarshal(input, &data)
+	if err := json.Unmarshal(input, &data); err != nil {
+		return fmt.Errorf("failed to unmarshal hex string: %w", err)
+	}
+
+	hexDigits := strings.TrimPrefix(data, "0x")
+	if len(hexDigits)%2 != 0 {
+		return fmt.Errorf("hex string of odd length %d", len(hexDigits))
+	}
 
-	res, _ := hex.DecodeString(strings.TrimPrefix(data, "0x"))
+	res, err := hex.DecodeString(hexDigits)
+	if err != nil {
+		return fmt.Errorf("failed to decode hex string: %w", err)
+	}
 
 	*h = res
 
@@ -145,6 +193,102 @@ type InclusionProof struct {
 	MerkleHashes       []*HexBytes     `json:"merkle_hashes"`
 }
 
+// ValidateMerkleHashLengths enforces that every hash in MerkleHashes is exactly 32 bytes long.
+// A malformed proof with truncated or oversized merkle hashes would otherwise be indistinguishable
+// from a valid one once decoded, since HexBytes.UnmarshalJSON itself accepts hex of any even length.
+func (p *InclusionProof) ValidateMerkleHashLengths() error {
+	for i, h := range p.MerkleHashes {
+		if h == nil {
+			return fmt.Errorf("merkle hash %d is nil", i)
+		}
+		if len(*h) != 32 {
+			return fmt.Errorf("merkle hash %d has invalid length %d, expected 32", i, len(*h))
+		}
+	}
+	return nil
+}
+
+// VerifyInclusionProof checks that every transaction referenced by proof.TransactionHashes is
+// included in the execution payload whose transactions root is txsRoot, by reconstructing each
+// leaf as the SSZ hash-tree-root of the underlying `Transaction` and running the standard
+// Merkle multiproof verifier over proof.GeneralizedIndexes and proof.MerkleHashes.
+//
+// cache is consulted to resolve each TransactionHashes[i] (a keccak tx hash) back to the raw
+// transaction bytes needed to compute the SSZ leaf, and to reject proofs for transactions that
+// were never registered as a constraint for slot.
+func VerifyInclusionProof(cache *ConstraintsCache, slot uint64, txsRoot phase0.Root, proof *InclusionProof) error {
+	if proof == nil {
+		return errors.New("nil inclusion proof")
+	}
+	if len(proof.TransactionHashes) == 0 {
+		return errors.New("inclusion proof has no transaction hashes")
+	}
+	if len(proof.TransactionHashes) != len(proof.GeneralizedIndexes) {
+		return errors.New("mismatched number of transaction hashes and generalized indexes")
+	}
+	if err := proof.ValidateMerkleHashLengths(); err != nil {
+		return fmt.Errorf("invalid inclusion proof: %w", err)
+	}
+
+	constraints, exists := cache.Get(slot)
+	if !exists {
+		return fmt.Errorf("no constraints cached for slot %d", slot)
+	}
+
+	leaves := make([][]byte, len(proof.TransactionHashes))
+	for i, txHash := range proof.TransactionHashes {
+		txRaw, isConstrained := constraints[gethCommon.Hash(txHash)]
+		if !isConstrained {
+			return fmt.Errorf("transaction %s is not a constraint for slot %d", txHash.String(), slot)
+		}
+
+		leaf, err := hashTreeRootTransaction(*txRaw)
+		if err != nil {
+			return fmt.Errorf("failed to compute leaf for transaction %s: %w", txHash.String(), err)
+		}
+		leaves[i] = leaf[:]
+	}
+
+	merkleHashes := make([][]byte, len(proof.MerkleHashes))
+	for i, h := range proof.MerkleHashes {
+		merkleHashes[i] = []byte(*h)
+	}
+
+	generalizedIndexes := make([]int, len(proof.GeneralizedIndexes))
+	for i, idx := range proof.GeneralizedIndexes {
+		generalizedIndexes[i] = int(idx)
+	}
+
+	if !fastSsz.VerifyMultiproof(txsRoot[:], merkleHashes, leaves, generalizedIndexes) {
+		return errors.New("inclusion proof failed multiproof verification")
+	}
+	return nil
+}
+
+// hashTreeRootTransaction computes the SSZ hash-tree-root of a single transaction encoded as a
+// `ByteList[MAX_BYTES_PER_TRANSACTION]`, matching the consensus-specs `Transaction` type.
+func hashTreeRootTransaction(tx HexTransaction) (phase0.Root, error) {
+	hh := fastSsz.NewHasher()
+	hh.PutBytesLimit(tx, maxBytesPerTransaction)
+	root, err := hh.HashRoot()
+	if err != nil {
+		return phase0.Root{}, err
+	}
+	return root, nil
+}
+
+// VerifyBidInclusionProof verifies bid's inclusion proof against txsRoot and the constraints
+// cached for slot. On failure it increments the inclusionProofFailures metric for relay so the
+// caller (SelectVerifiedBid) can drop the bid from the auction and fall back to
+// LocalPayloadBuilder if no other bid survives.
+func VerifyBidInclusionProof(cache *ConstraintsCache, slot uint64, relay string, txsRoot phase0.Root, bid *VersionedSignedBuilderBidWithProofs) error {
+	if err := VerifyInclusionProof(cache, slot, txsRoot, bid.Proofs); err != nil {
+		inclusionProofFailures.WithLabelValues(relay).Inc()
+		return fmt.Errorf("inclusion proof verification failed for relay %s: %w", relay, err)
+	}
+	return nil
+}
+
 // InclusionProofFromMultiProof converts a fastssz.Multiproof into an InclusionProof, without
 // filling the TransactionHashes
 func InclusionProofFromMultiProof(mp *fastSsz.Multiproof) *InclusionProof {
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// FuzzHexBytesUnmarshalJSON exercises HexBytes.UnmarshalJSON against truncated, non-hex and
+// oversized inputs to make sure it always either decodes correctly or returns an error, and
+// never panics.
+func FuzzHexBytesUnmarshalJSON(f *testing.F) {
+	f.Add(`"0x"`)
+	f.Add(`"0xdeadbeef"`)
+	f.Add(`"0x0"`)
+	f.Add(`"0xzz"`)
+	f.Add(`"deadbeef"`)
+	f.Add(`0xdeadbeef`)
+	f.Add(``)
+	f.Add(`"`)
+	f.Add(`"0x` + string(make([]byte, 1<<16)) + `"`)
+
+	f.Fuzz(func(t *testing.T, input string) {
+		var h HexBytes
+		err := h.UnmarshalJSON([]byte(input))
+		if err != nil {
+			return
+		}
+
+		// A successful decode must round-trip through MarshalJSON/UnmarshalJSON.
+		remarshaled, err := h.MarshalJSON()
+		if err != nil {
+			t.Fatalf("MarshalJSON failed after successful UnmarshalJSON(%q): %v", input, err)
+		}
+
+		var roundTripped HexBytes
+		if err := roundTripped.UnmarshalJSON(remarshaled); err != nil {
+			t.Fatalf("UnmarshalJSON failed on its own MarshalJSON output %q (from input %q): %v", remarshaled, input, err)
+		}
+		if !h.Equal(roundTripped) {
+			t.Fatalf("round-trip mismatch: got %x, want %x", roundTripped, h)
+		}
+	})
+}
+
+// FuzzInclusionProofMerkleHashesUnmarshalJSON exercises InclusionProof JSON decoding, which
+// relies on HexBytes.UnmarshalJSON for every element of MerkleHashes, with a truncated/malformed
+// list of merkle hashes.
+func FuzzInclusionProofMerkleHashesUnmarshalJSON(f *testing.F) {
+	f.Add(`[]`)
+	f.Add(`["0x` + string(make([]byte, 64)) + `"]`)
+	f.Add(`["0xzz"]`)
+	f.Add(`[null]`)
+	f.Add(`["0x00"]`)
+
+	f.Fuzz(func(t *testing.T, input string) {
+		var hashes []*HexBytes
+		if err := json.Unmarshal([]byte(input), &hashes); err != nil {
+			return
+		}
+
+		proof := InclusionProof{MerkleHashes: hashes}
+		_ = proof.ValidateMerkleHashLengths()
+	})
+}
@@ -0,0 +1,738 @@
+package server
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	fastSsz "github.com/ferranbt/fastssz"
+
+	"github.com/attestantio/go-builder-client/api/deneb"
+	"github.com/attestantio/go-builder-client/api/electra"
+	builderSpec "github.com/attestantio/go-builder-client/spec"
+	consensusSpec "github.com/attestantio/go-eth2-client/spec"
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+)
+
+// computeSigningRoot mixes obj's SSZ hash-tree-root with domain, producing the root that BLS
+// signatures over constraints-API messages are computed against.
+func computeSigningRoot(obj fastSsz.HashRoot, domain phase0.Domain) ([32]byte, error) {
+	objectRoot, err := obj.HashTreeRoot()
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	signingData := make([]byte, 0, 64)
+	signingData = append(signingData, objectRoot[:]...)
+	signingData = append(signingData, domain[:]...)
+	return sha256.Sum256(signingData), nil
+}
+
+// maxConstraintsPerSlot bounds the number of transactions a single ConstraintsMessage may
+// carry, mirroring the cap relays enforce on submit_constraint.
+const maxConstraintsPerSlot = 256
+
+// maxInclusionProofLeaves bounds the number of entries InclusionProof's lists may carry.
+const maxInclusionProofLeaves = 1 << 20
+
+// This file contains hand-written fastssz-compatible Marshal/Unmarshal/HashTreeRoot
+// implementations for the constraints-API types, so that SignedConstraints, Delegation,
+// Revocation and VersionedSignedBuilderBidWithProofs can be negotiated as
+// application/octet-stream in addition to JSON.
+
+// --- Delegation ---
+
+// SizeSSZ returns the ssz-encoded size of Delegation.
+func (d *Delegation) SizeSSZ() int {
+	return 1 + 48 + 48
+}
+
+// MarshalSSZ ssz marshals Delegation.
+func (d *Delegation) MarshalSSZ() ([]byte, error) {
+	return d.MarshalSSZTo(make([]byte, 0, d.SizeSSZ()))
+}
+
+// MarshalSSZTo ssz marshals Delegation into dst and returns the extended buffer.
+func (d *Delegation) MarshalSSZTo(dst []byte) ([]byte, error) {
+	dst = append(dst, d.Action)
+	dst = append(dst, d.ValidatorPubkey[:]...)
+	dst = append(dst, d.DelegateePubkey[:]...)
+	return dst, nil
+}
+
+// UnmarshalSSZ ssz unmarshals Delegation.
+func (d *Delegation) UnmarshalSSZ(buf []byte) error {
+	if len(buf) != d.SizeSSZ() {
+		return fastSsz.ErrSize
+	}
+	d.Action = buf[0]
+	copy(d.ValidatorPubkey[:], buf[1:49])
+	copy(d.DelegateePubkey[:], buf[49:97])
+	return nil
+}
+
+// HashTreeRoot ssz hashes Delegation.
+func (d *Delegation) HashTreeRoot() ([32]byte, error) {
+	return fastSsz.HashWithDefaultHasher(d)
+}
+
+// HashTreeRootWith ssz hashes Delegation with a specified hasher.
+func (d *Delegation) HashTreeRootWith(hh *fastSsz.Hasher) error {
+	indx := hh.Index()
+	hh.PutUint8(d.Action)
+	hh.PutBytes(d.ValidatorPubkey[:])
+	hh.PutBytes(d.DelegateePubkey[:])
+	hh.Merkleize(indx)
+	return nil
+}
+
+// --- Revocation ---
+// Revocation has the same shape as Delegation.
+
+// SizeSSZ returns the ssz-encoded size of Revocation.
+func (r *Revocation) SizeSSZ() int {
+	return 1 + 48 + 48
+}
+
+// MarshalSSZ ssz marshals Revocation.
+func (r *Revocation) MarshalSSZ() ([]byte, error) {
+	return r.MarshalSSZTo(make([]byte, 0, r.SizeSSZ()))
+}
+
+// MarshalSSZTo ssz marshals Revocation into dst and returns the extended buffer.
+func (r *Revocation) MarshalSSZTo(dst []byte) ([]byte, error) {
+	dst = append(dst, r.Action)
+	dst = append(dst, r.ValidatorPubkey[:]...)
+	dst = append(dst, r.DelegateePubkey[:]...)
+	return dst, nil
+}
+
+// UnmarshalSSZ ssz unmarshals Revocation.
+func (r *Revocation) UnmarshalSSZ(buf []byte) error {
+	if len(buf) != r.SizeSSZ() {
+		return fastSsz.ErrSize
+	}
+	r.Action = buf[0]
+	copy(r.ValidatorPubkey[:], buf[1:49])
+	copy(r.DelegateePubkey[:], buf[49:97])
+	return nil
+}
+
+// HashTreeRoot ssz hashes Revocation.
+func (r *Revocation) HashTreeRoot() ([32]byte, error) {
+	return fastSsz.HashWithDefaultHasher(r)
+}
+
+// HashTreeRootWith ssz hashes Revocation with a specified hasher.
+func (r *Revocation) HashTreeRootWith(hh *fastSsz.Hasher) error {
+	indx := hh.Index()
+	hh.PutUint8(r.Action)
+	hh.PutBytes(r.ValidatorPubkey[:])
+	hh.PutBytes(r.DelegateePubkey[:])
+	hh.Merkleize(indx)
+	return nil
+}
+
+// --- SignedDelegation ---
+
+// SizeSSZ returns the ssz-encoded size of SignedDelegation.
+func (s *SignedDelegation) SizeSSZ() int {
+	return s.Message.SizeSSZ() + 96
+}
+
+// MarshalSSZ ssz marshals SignedDelegation.
+func (s *SignedDelegation) MarshalSSZ() ([]byte, error) {
+	return s.MarshalSSZTo(make([]byte, 0, s.SizeSSZ()))
+}
+
+// MarshalSSZTo ssz marshals SignedDelegation into dst and returns the extended buffer.
+func (s *SignedDelegation) MarshalSSZTo(dst []byte) ([]byte, error) {
+	dst, err := s.Message.MarshalSSZTo(dst)
+	if err != nil {
+		return nil, err
+	}
+	dst = append(dst, s.Signature[:]...)
+	return dst, nil
+}
+
+// UnmarshalSSZ ssz unmarshals SignedDelegation.
+func (s *SignedDelegation) UnmarshalSSZ(buf []byte) error {
+	if len(buf) != s.SizeSSZ() {
+		return fastSsz.ErrSize
+	}
+	if err := s.Message.UnmarshalSSZ(buf[:s.Message.SizeSSZ()]); err != nil {
+		return err
+	}
+	copy(s.Signature[:], buf[s.Message.SizeSSZ():])
+	return nil
+}
+
+// HashTreeRoot ssz hashes SignedDelegation.
+func (s *SignedDelegation) HashTreeRoot() ([32]byte, error) {
+	return fastSsz.HashWithDefaultHasher(s)
+}
+
+// HashTreeRootWith ssz hashes SignedDelegation with a specified hasher.
+func (s *SignedDelegation) HashTreeRootWith(hh *fastSsz.Hasher) error {
+	indx := hh.Index()
+	if err := s.Message.HashTreeRootWith(hh); err != nil {
+		return err
+	}
+	hh.PutBytes(s.Signature[:])
+	hh.Merkleize(indx)
+	return nil
+}
+
+// --- SignedRevocation ---
+
+// SizeSSZ returns the ssz-encoded size of SignedRevocation.
+func (s *SignedRevocation) SizeSSZ() int {
+	return s.Message.SizeSSZ() + 96
+}
+
+// MarshalSSZ ssz marshals SignedRevocation.
+func (s *SignedRevocation) MarshalSSZ() ([]byte, error) {
+	return s.MarshalSSZTo(make([]byte, 0, s.SizeSSZ()))
+}
+
+// MarshalSSZTo ssz marshals SignedRevocation into dst and returns the extended buffer.
+func (s *SignedRevocation) MarshalSSZTo(dst []byte) ([]byte, error) {
+	dst, err := s.Message.MarshalSSZTo(dst)
+	if err != nil {
+		return nil, err
+	}
+	dst = append(dst, s.Signature[:]...)
+	return dst, nil
+}
+
+// UnmarshalSSZ ssz unmarshals SignedRevocation.
+func (s *SignedRevocation) UnmarshalSSZ(buf []byte) error {
+	if len(buf) != s.SizeSSZ() {
+		return fastSsz.ErrSize
+	}
+	if err := s.Message.UnmarshalSSZ(buf[:s.Message.SizeSSZ()]); err != nil {
+		return err
+	}
+	copy(s.Signature[:], buf[s.Message.SizeSSZ():])
+	return nil
+}
+
+// HashTreeRoot ssz hashes SignedRevocation.
+func (s *SignedRevocation) HashTreeRoot() ([32]byte, error) {
+	return fastSsz.HashWithDefaultHasher(s)
+}
+
+// HashTreeRootWith ssz hashes SignedRevocation with a specified hasher.
+func (s *SignedRevocation) HashTreeRootWith(hh *fastSsz.Hasher) error {
+	indx := hh.Index()
+	if err := s.Message.HashTreeRootWith(hh); err != nil {
+		return err
+	}
+	hh.PutBytes(s.Signature[:])
+	hh.Merkleize(indx)
+	return nil
+}
+
+// --- ConstraintsMessage ---
+
+// SizeSSZ returns the ssz-encoded size of ConstraintsMessage.
+func (m *ConstraintsMessage) SizeSSZ() int {
+	size := 48 + 8 + 1 + 4
+	for _, tx := range m.Transactions {
+		size += 4 + len(*tx)
+	}
+	return size
+}
+
+// MarshalSSZ ssz marshals ConstraintsMessage.
+func (m *ConstraintsMessage) MarshalSSZ() ([]byte, error) {
+	return m.MarshalSSZTo(make([]byte, 0, m.SizeSSZ()))
+}
+
+// MarshalSSZTo ssz marshals ConstraintsMessage into dst and returns the extended buffer.
+func (m *ConstraintsMessage) MarshalSSZTo(dst []byte) ([]byte, error) {
+	if len(m.Transactions) > maxConstraintsPerSlot {
+		return nil, fastSsz.ErrListTooBigFn("ConstraintsMessage.Transactions", len(m.Transactions), maxConstraintsPerSlot)
+	}
+
+	offset := 48 + 8 + 1 + 4
+
+	dst = append(dst, m.Pubkey[:]...)
+	dst = fastSsz.MarshalUint64(dst, m.Slot)
+	dst = fastSsz.MarshalBool(dst, m.Top)
+
+	// Offset (3) 'Transactions'
+	dst = fastSsz.WriteOffset(dst, offset)
+	for _, tx := range m.Transactions {
+		offset += 4 + len(*tx)
+	}
+
+	// Field (3) 'Transactions': nested offsets followed by the raw transaction bytes.
+	nestedOffset := 4 * len(m.Transactions)
+	for _, tx := range m.Transactions {
+		dst = fastSsz.WriteOffset(dst, nestedOffset)
+		nestedOffset += len(*tx)
+	}
+	for _, tx := range m.Transactions {
+		dst = append(dst, *tx...)
+	}
+
+	return dst, nil
+}
+
+// UnmarshalSSZ ssz unmarshals ConstraintsMessage.
+func (m *ConstraintsMessage) UnmarshalSSZ(buf []byte) error {
+	if len(buf) < 48+8+1+4 {
+		return fastSsz.ErrSize
+	}
+
+	copy(m.Pubkey[:], buf[0:48])
+	m.Slot = fastSsz.UnmarshallUint64(buf[48:56])
+	m.Top = buf[56] != 0
+
+	o3 := int(readOffset(buf[57:61]))
+	if o3 > len(buf) {
+		return fastSsz.ErrOffset
+	}
+
+	return m.unmarshalTransactions(buf[o3:])
+}
+
+// unmarshalTransactions decodes the offset-prefixed list of variable-length transactions
+// found in the variable-size part of a ConstraintsMessage.
+func (m *ConstraintsMessage) unmarshalTransactions(buf []byte) error {
+	items, err := unmarshalVariableByteList(buf, maxConstraintsPerSlot)
+	if err != nil {
+		return err
+	}
+
+	m.Transactions = make([]*HexTransaction, len(items))
+	for i, item := range items {
+		tx := HexTransaction(item)
+		m.Transactions[i] = &tx
+	}
+
+	return nil
+}
+
+// readOffset decodes a 4-byte little-endian SSZ offset.
+func readOffset(b []byte) uint32 {
+	return uint32(b[0]) | uint32(b[1])<<8 | uint32(b[2])<<16 | uint32(b[3])<<24
+}
+
+// unmarshalVariableByteList decodes a packed, offset-prefixed list of variable-length byte
+// slices — the on-wire shape shared by ConstraintsMessage.Transactions and
+// InclusionProof.MerkleHashes — enforcing that it carries at most maxItems elements.
+func unmarshalVariableByteList(buf []byte, maxItems int) ([][]byte, error) {
+	if len(buf) == 0 {
+		return nil, nil
+	}
+
+	firstOffset := int(readOffset(buf[0:4]))
+	if firstOffset%4 != 0 {
+		return nil, fastSsz.ErrInvalidVariableOffset
+	}
+	count := firstOffset / 4
+	if count > maxItems {
+		return nil, fastSsz.ErrListTooBigFn("list", count, maxItems)
+	}
+
+	offsets := make([]int, count+1)
+	for i := 0; i < count; i++ {
+		offsets[i] = int(readOffset(buf[i*4 : i*4+4]))
+	}
+	offsets[count] = len(buf)
+
+	items := make([][]byte, count)
+	for i := 0; i < count; i++ {
+		if offsets[i] > offsets[i+1] || offsets[i+1] > len(buf) {
+			return nil, fastSsz.ErrOffset
+		}
+		items[i] = append([]byte(nil), buf[offsets[i]:offsets[i+1]]...)
+	}
+
+	return items, nil
+}
+
+// HashTreeRoot ssz hashes ConstraintsMessage.
+func (m *ConstraintsMessage) HashTreeRoot() ([32]byte, error) {
+	return fastSsz.HashWithDefaultHasher(m)
+}
+
+// HashTreeRootWith ssz hashes ConstraintsMessage with a specified hasher.
+func (m *ConstraintsMessage) HashTreeRootWith(hh *fastSsz.Hasher) error {
+	indx := hh.Index()
+
+	hh.PutBytes(m.Pubkey[:])
+	hh.PutUint64(m.Slot)
+	hh.PutBool(m.Top)
+
+	{
+		subIndx := hh.Index()
+		num := uint64(len(m.Transactions))
+		if num > maxConstraintsPerSlot {
+			return fastSsz.ErrListTooBigFn("ConstraintsMessage.Transactions", int(num), maxConstraintsPerSlot)
+		}
+		// Each transaction is itself a List[byte, MAX_BYTES_PER_TRANSACTION]: PutBytesLimit
+		// merkleizes it to its own 32-byte root and appends that, matching the leaf
+		// hashTreeRootTransaction computes for inclusion-proof verification. Appending the raw
+		// bytes here instead would flatten every transaction into one undifferentiated blob and
+		// produce a root nothing else in the codebase (or any relay) would agree with.
+		for _, tx := range m.Transactions {
+			hh.PutBytesLimit(*tx, maxBytesPerTransaction)
+		}
+		hh.MerkleizeWithMixin(subIndx, num, maxConstraintsPerSlot)
+	}
+
+	hh.Merkleize(indx)
+	return nil
+}
+
+// --- SignedConstraints ---
+
+// SizeSSZ returns the ssz-encoded size of SignedConstraints.
+func (s *SignedConstraints) SizeSSZ() int {
+	return 4 + 96 + s.Message.SizeSSZ()
+}
+
+// MarshalSSZ ssz marshals SignedConstraints.
+func (s *SignedConstraints) MarshalSSZ() ([]byte, error) {
+	return s.MarshalSSZTo(make([]byte, 0, s.SizeSSZ()))
+}
+
+// MarshalSSZTo ssz marshals SignedConstraints into dst and returns the extended buffer.
+//
+// Message is variable-size (it carries Transactions, a list) and Signature is fixed-size, so
+// the fixed part of this container is a 4-byte offset for Message followed by the 96-byte
+// Signature, with Message's bytes appended after Signature in the variable part — the same
+// fixed/variable split ConstraintsMessage itself uses for Transactions.
+func (s *SignedConstraints) MarshalSSZTo(dst []byte) ([]byte, error) {
+	offset := 4 + 96
+
+	// Offset (0) 'Message'
+	dst = fastSsz.WriteOffset(dst, offset)
+
+	// Field (1) 'Signature'
+	dst = append(dst, s.Signature[:]...)
+
+	// Field (0) 'Message'
+	dst, err := s.Message.MarshalSSZTo(dst)
+	if err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+// UnmarshalSSZ ssz unmarshals SignedConstraints.
+func (s *SignedConstraints) UnmarshalSSZ(buf []byte) error {
+	if len(buf) < 4+96 {
+		return fastSsz.ErrSize
+	}
+
+	o0 := int(readOffset(buf[0:4]))
+	if o0 > len(buf) {
+		return fastSsz.ErrOffset
+	}
+
+	copy(s.Signature[:], buf[4:100])
+
+	return s.Message.UnmarshalSSZ(buf[o0:])
+}
+
+// HashTreeRoot ssz hashes SignedConstraints.
+func (s *SignedConstraints) HashTreeRoot() ([32]byte, error) {
+	return fastSsz.HashWithDefaultHasher(s)
+}
+
+// HashTreeRootWith ssz hashes SignedConstraints with a specified hasher.
+func (s *SignedConstraints) HashTreeRootWith(hh *fastSsz.Hasher) error {
+	indx := hh.Index()
+	if err := s.Message.HashTreeRootWith(hh); err != nil {
+		return err
+	}
+	hh.PutBytes(s.Signature[:])
+	hh.Merkleize(indx)
+	return nil
+}
+
+// --- InclusionProof ---
+
+// MarshalSSZ ssz marshals InclusionProof.
+func (p *InclusionProof) MarshalSSZ() ([]byte, error) {
+	return p.MarshalSSZTo(nil)
+}
+
+// MarshalSSZTo ssz marshals InclusionProof into dst and returns the extended buffer.
+func (p *InclusionProof) MarshalSSZTo(dst []byte) ([]byte, error) {
+	if len(p.TransactionHashes) > maxInclusionProofLeaves {
+		return nil, fastSsz.ErrListTooBigFn("InclusionProof.TransactionHashes", len(p.TransactionHashes), maxInclusionProofLeaves)
+	}
+	if len(p.MerkleHashes) > maxInclusionProofLeaves {
+		return nil, fastSsz.ErrListTooBigFn("InclusionProof.MerkleHashes", len(p.MerkleHashes), maxInclusionProofLeaves)
+	}
+
+	offset := 4 + 4 + 4
+
+	// Offset (0) 'TransactionHashes'
+	dst = fastSsz.WriteOffset(dst, offset)
+	offset += len(p.TransactionHashes) * 32
+
+	// Offset (1) 'GeneralizedIndexes'
+	dst = fastSsz.WriteOffset(dst, offset)
+	offset += len(p.GeneralizedIndexes) * 8
+
+	// Offset (2) 'MerkleHashes'
+	dst = fastSsz.WriteOffset(dst, offset)
+	for _, h := range p.MerkleHashes {
+		offset += 4 + len(*h)
+	}
+
+	// Field (0) 'TransactionHashes'
+	for _, h := range p.TransactionHashes {
+		dst = append(dst, h[:]...)
+	}
+
+	// Field (1) 'GeneralizedIndexes'
+	for _, idx := range p.GeneralizedIndexes {
+		dst = fastSsz.MarshalUint64(dst, idx)
+	}
+
+	// Field (2) 'MerkleHashes'
+	nestedOffset := 4 * len(p.MerkleHashes)
+	for _, h := range p.MerkleHashes {
+		dst = fastSsz.WriteOffset(dst, nestedOffset)
+		nestedOffset += len(*h)
+	}
+	for _, h := range p.MerkleHashes {
+		dst = append(dst, *h...)
+	}
+
+	return dst, nil
+}
+
+// UnmarshalSSZ ssz unmarshals InclusionProof.
+func (p *InclusionProof) UnmarshalSSZ(buf []byte) error {
+	if len(buf) < 12 {
+		return fastSsz.ErrSize
+	}
+
+	o0 := int(readOffset(buf[0:4]))
+	o1 := int(readOffset(buf[4:8]))
+	o2 := int(readOffset(buf[8:12]))
+	if o0 > o1 || o1 > o2 || o2 > len(buf) {
+		return fastSsz.ErrOffset
+	}
+
+	// Field (0) 'TransactionHashes': fixed-size 32-byte elements.
+	txHashesBuf := buf[o0:o1]
+	if len(txHashesBuf)%32 != 0 {
+		return fastSsz.ErrSize
+	}
+	txCount := len(txHashesBuf) / 32
+	if txCount > maxInclusionProofLeaves {
+		return fastSsz.ErrListTooBigFn("InclusionProof.TransactionHashes", txCount, maxInclusionProofLeaves)
+	}
+	p.TransactionHashes = make([]phase0.Hash32, txCount)
+	for i := 0; i < txCount; i++ {
+		copy(p.TransactionHashes[i][:], txHashesBuf[i*32:(i+1)*32])
+	}
+
+	// Field (1) 'GeneralizedIndexes': fixed-size 8-byte elements.
+	idxBuf := buf[o1:o2]
+	if len(idxBuf)%8 != 0 {
+		return fastSsz.ErrSize
+	}
+	idxCount := len(idxBuf) / 8
+	if idxCount > maxInclusionProofLeaves {
+		return fastSsz.ErrListTooBigFn("InclusionProof.GeneralizedIndexes", idxCount, maxInclusionProofLeaves)
+	}
+	p.GeneralizedIndexes = make([]uint64, idxCount)
+	for i := 0; i < idxCount; i++ {
+		p.GeneralizedIndexes[i] = fastSsz.UnmarshallUint64(idxBuf[i*8 : i*8+8])
+	}
+
+	// Field (2) 'MerkleHashes': offset-prefixed variable-length elements.
+	merkleItems, err := unmarshalVariableByteList(buf[o2:], maxInclusionProofLeaves)
+	if err != nil {
+		return err
+	}
+	p.MerkleHashes = make([]*HexBytes, len(merkleItems))
+	for i, item := range merkleItems {
+		h := HexBytes(item)
+		p.MerkleHashes[i] = &h
+	}
+
+	return nil
+}
+
+// HashTreeRoot ssz hashes InclusionProof.
+func (p *InclusionProof) HashTreeRoot() ([32]byte, error) {
+	return fastSsz.HashWithDefaultHasher(p)
+}
+
+// HashTreeRootWith ssz hashes InclusionProof with a specified hasher.
+func (p *InclusionProof) HashTreeRootWith(hh *fastSsz.Hasher) error {
+	indx := hh.Index()
+
+	{
+		subIndx := hh.Index()
+		for _, h := range p.TransactionHashes {
+			hh.Append(h[:])
+		}
+		hh.FillUpTo32()
+		hh.MerkleizeWithMixin(subIndx, uint64(len(p.TransactionHashes)), maxInclusionProofLeaves)
+	}
+
+	{
+		subIndx := hh.Index()
+		for _, idx := range p.GeneralizedIndexes {
+			hh.AppendUint64(idx)
+		}
+		hh.FillUpTo32()
+		hh.MerkleizeWithMixin(subIndx, uint64(len(p.GeneralizedIndexes)), maxInclusionProofLeaves)
+	}
+
+	{
+		subIndx := hh.Index()
+		for _, h := range p.MerkleHashes {
+			hh.AppendBytes32(*h)
+		}
+		hh.FillUpTo32()
+		hh.MerkleizeWithMixin(subIndx, uint64(len(p.MerkleHashes)), maxInclusionProofLeaves)
+	}
+
+	hh.Merkleize(indx)
+	return nil
+}
+
+// --- VersionedSignedBuilderBidWithProofs ---
+
+// MarshalSSZ ssz marshals VersionedSignedBuilderBidWithProofs, prefixing the inner
+// version-specific bid with a 1-byte fork selector so a decoder can dispatch without
+// first inspecting JSON.
+func (v *VersionedSignedBuilderBidWithProofs) MarshalSSZ() ([]byte, error) {
+	var (
+		inner fastSsz.Marshaler
+		proof []byte
+		err   error
+	)
+
+	switch v.Version {
+	case consensusSpec.DataVersionDeneb:
+		inner = v.Deneb
+	case consensusSpec.DataVersionElectra:
+		inner = v.Electra
+	default:
+		return nil, fastSsz.ErrBytesLength
+	}
+
+	innerBytes, err := inner.MarshalSSZ()
+	if err != nil {
+		return nil, err
+	}
+
+	if v.Proofs != nil {
+		proof, err = v.Proofs.MarshalSSZ()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	dst := make([]byte, 0, 1+4+len(innerBytes)+len(proof))
+	dst = append(dst, uint8(v.Version))
+	dst = fastSsz.MarshalUint32(dst, uint32(len(innerBytes)))
+	dst = append(dst, innerBytes...)
+	dst = append(dst, proof...)
+	return dst, nil
+}
+
+// UnmarshalSSZ ssz unmarshals VersionedSignedBuilderBidWithProofs, dispatching the
+// version-specific inner bid based on the leading fork selector MarshalSSZ writes and treating
+// any bytes left over after it as an InclusionProof.
+func (v *VersionedSignedBuilderBidWithProofs) UnmarshalSSZ(buf []byte) error {
+	if len(buf) < 1+4 {
+		return fastSsz.ErrSize
+	}
+
+	version := consensusSpec.DataVersion(buf[0])
+	innerLen := int(readOffset(buf[1:5]))
+	if 5+innerLen > len(buf) {
+		return fastSsz.ErrSize
+	}
+	innerBuf := buf[5 : 5+innerLen]
+	proofBuf := buf[5+innerLen:]
+
+	v.VersionedSignedBuilderBid = &builderSpec.VersionedSignedBuilderBid{Version: version}
+
+	switch version {
+	case consensusSpec.DataVersionDeneb:
+		inner := new(deneb.SignedBuilderBid)
+		if err := inner.UnmarshalSSZ(innerBuf); err != nil {
+			return err
+		}
+		v.Deneb = inner
+	case consensusSpec.DataVersionElectra:
+		inner := new(electra.SignedBuilderBid)
+		if err := inner.UnmarshalSSZ(innerBuf); err != nil {
+			return err
+		}
+		v.Electra = inner
+	default:
+		return fmt.Errorf("unknown or unsupported data version %d", version)
+	}
+
+	if len(proofBuf) == 0 {
+		return nil
+	}
+
+	proof := new(InclusionProof)
+	if err := proof.UnmarshalSSZ(proofBuf); err != nil {
+		return err
+	}
+	v.Proofs = proof
+
+	return nil
+}
+
+// HashTreeRoot ssz hashes VersionedSignedBuilderBidWithProofs.
+func (v *VersionedSignedBuilderBidWithProofs) HashTreeRoot() ([32]byte, error) {
+	return fastSsz.HashWithDefaultHasher(v)
+}
+
+// HashTreeRootWith ssz hashes VersionedSignedBuilderBidWithProofs with a specified hasher,
+// mixing in the fork version, the inner version-specific bid's root, and the inclusion proof's
+// root (or the zero root, if none is attached).
+func (v *VersionedSignedBuilderBidWithProofs) HashTreeRootWith(hh *fastSsz.Hasher) error {
+	indx := hh.Index()
+
+	var inner fastSsz.HashRoot
+	switch v.Version {
+	case consensusSpec.DataVersionDeneb:
+		inner = v.Deneb
+	case consensusSpec.DataVersionElectra:
+		inner = v.Electra
+	default:
+		return fmt.Errorf("unknown or unsupported data version %d", v.Version)
+	}
+
+	hh.PutUint8(uint8(v.Version))
+
+	innerRoot, err := inner.HashTreeRoot()
+	if err != nil {
+		return err
+	}
+	hh.PutBytes(innerRoot[:])
+
+	if v.Proofs != nil {
+		proofRoot, err := v.Proofs.HashTreeRoot()
+		if err != nil {
+			return err
+		}
+		hh.PutBytes(proofRoot[:])
+	} else {
+		hh.PutBytes(make([]byte, 32))
+	}
+
+	hh.Merkleize(indx)
+	return nil
+}
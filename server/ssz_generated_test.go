@@ -0,0 +1,126 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	boostBls "github.com/flashbots/go-boost-utils/bls"
+
+	"github.com/attestantio/go-eth2-client/spec/phase0"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// signedTxForTest returns a real RLP-encoded, signed legacy transaction distinguished by nonce,
+// matching the wire format ConstraintsMessage.Transactions carries.
+func signedTxForTest(t *testing.T, key *ecdsa.PrivateKey, nonce uint64) *HexTransaction {
+	t.Helper()
+
+	tx := types.NewTx(&types.LegacyTx{
+		Nonce:    nonce,
+		To:       &gethCommonZeroAddr,
+		Value:    big.NewInt(0),
+		Gas:      21000,
+		GasPrice: big.NewInt(1),
+	})
+
+	signed, err := types.SignTx(tx, types.HomesteadSigner{}, key)
+	if err != nil {
+		t.Fatalf("failed to sign test transaction: %v", err)
+	}
+
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal test transaction: %v", err)
+	}
+
+	hex := HexTransaction(raw)
+	return &hex
+}
+
+// TestConstraintsMessageHashTreeRootWithTransactions exercises HashTreeRoot with a populated
+// Transactions list, the case that previously flattened every transaction into one
+// undifferentiated byte blob instead of merkleizing each as its own
+// List[byte, MAX_BYTES_PER_TRANSACTION] before the outer list root.
+func TestConstraintsMessageHashTreeRootWithTransactions(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate test key: %v", err)
+	}
+
+	msg := ConstraintsMessage{
+		Slot: 123,
+		Top:  true,
+		Transactions: []*HexTransaction{
+			signedTxForTest(t, key, 0),
+			signedTxForTest(t, key, 1),
+		},
+	}
+
+	root, err := msg.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("HashTreeRoot failed: %v", err)
+	}
+
+	// hashTreeRootTransaction is the leaf VerifyInclusionProof computes for a transaction, and
+	// HashTreeRoot merkleizes each transaction with the same PutBytesLimit call. This checks only
+	// that the leaf is non-zero; it does not compare it with the leaves inside root.
+	leaf, err := hashTreeRootTransaction(*msg.Transactions[0])
+	if err != nil {
+		t.Fatalf("hashTreeRootTransaction failed: %v", err)
+	}
+	if leaf == (phase0.Root{}) {
+		t.Fatalf("expected a non-zero per-transaction leaf")
+	}
+
+	// Round-tripping through the wire encoding must reproduce the same root.
+	raw, err := msg.MarshalSSZ()
+	if err != nil {
+		t.Fatalf("MarshalSSZ failed: %v", err)
+	}
+
+	var decoded ConstraintsMessage
+	if err := decoded.UnmarshalSSZ(raw); err != nil {
+		t.Fatalf("UnmarshalSSZ failed: %v", err)
+	}
+
+	decodedRoot, err := decoded.HashTreeRoot()
+	if err != nil {
+		t.Fatalf("HashTreeRoot on decoded message failed: %v", err)
+	}
+	if root != decodedRoot {
+		t.Fatalf("root mismatch after round-trip: got %x, want %x", decodedRoot, root)
+	}
+
+	// Sign the canonical root with a real BLS keypair and verify it through
+	// SignedConstraints.VerifySignature, the same path a relay streaming constraints or a
+	// delegatee submitting them would exercise.
+	sk, pk, err := boostBls.GenerateNewKeypair()
+	if err != nil {
+		t.Fatalf("failed to generate BLS keypair: %v", err)
+	}
+
+	var pubkey phase0.BLSPubKey
+	copy(pubkey[:], pk.Compress())
+	msg.Pubkey = pubkey
+
+	var domain phase0.Domain
+	signingRoot, err := computeSigningRoot(&msg, domain)
+	if err != nil {
+		t.Fatalf("computeSigningRoot failed: %v", err)
+	}
+
+	sig := boostBls.SignMessage(signingRoot[:], sk)
+
+	signed := SignedConstraints{Message: msg}
+	copy(signed.Signature[:], sig.Compress())
+
+	ok, err := signed.VerifySignature(domain)
+	if err != nil {
+		t.Fatalf("VerifySignature failed: %v", err)
+	}
+	if !ok {
+		t.Fatalf("expected a real BLS signature over a populated-transactions message to verify")
+	}
+}